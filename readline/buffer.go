@@ -0,0 +1,459 @@
+package readline
+
+import (
+	"fmt"
+	"io"
+	"unicode"
+)
+
+// Buffer holds the in-progress line and cursor position for a single
+// Readline call, and is responsible for keeping the terminal in sync with
+// its contents as it is edited.
+type Buffer struct {
+	Prompt *Prompt
+	Writer io.Writer
+	Buf    []rune
+	Pos    int
+
+	// Highlighter, if set, returns line re-rendered with ANSI color
+	// codes (e.g. to pick out /commands, quoted strings, file paths).
+	// It must not add, remove, or reorder visible runes - only wrap them
+	// in escape sequences - since cursor math still counts plain runes.
+	Highlighter func(line []rune, pos int) string
+
+	// Hinter, if set, returns a fish-style ghost suggestion for line,
+	// drawn in ColorGrey after the cursor when it sits at the end of the
+	// line. An empty return means no hint.
+	Hinter func(line []rune) string
+}
+
+// NewBuffer creates an empty Buffer bound to the given prompt (drawn again
+// by a full redraw and by ClearScreen), rendering through w.
+func NewBuffer(prompt *Prompt, w io.Writer) (*Buffer, error) {
+	return &Buffer{
+		Prompt: prompt,
+		Writer: w,
+		Buf:    make([]rune, 0, 64),
+	}, nil
+}
+
+// write renders s through the buffer's configured Writer.
+func (b *Buffer) write(s string) {
+	fmt.Fprint(b.Writer, s)
+}
+
+// IsEmpty reports whether the buffer has no content.
+func (b *Buffer) IsEmpty() bool {
+	return len(b.Buf) == 0
+}
+
+// DisplaySize returns the number of runes currently in the buffer.
+func (b *Buffer) DisplaySize() int {
+	return len(b.Buf)
+}
+
+// String returns the buffer contents as a string.
+func (b *Buffer) String() string {
+	return string(b.Buf)
+}
+
+// decorated reports whether a Highlighter or Hinter is configured, so
+// edits must fall back to a full line redraw() instead of a cheap
+// incremental write.
+func (b *Buffer) decorated() bool {
+	return b.Highlighter != nil || b.Hinter != nil
+}
+
+// redraw rewrites the entire current line from column zero: the prompt,
+// the buffer run through Highlighter (if set), and a grey Hinter
+// suggestion (if the cursor is at the end of the line), then repositions
+// the cursor. It's the only redraw path that understands the hint, since
+// the hint's visible width has to be walked back over to land the cursor
+// in the right place.
+func (b *Buffer) redraw() {
+	b.write("\r" + ClearToEOL)
+	if b.Prompt != nil {
+		b.write(b.Prompt.prompt())
+	}
+
+	line := string(b.Buf)
+	if b.Highlighter != nil {
+		line = b.Highlighter(b.Buf, b.Pos)
+	}
+	b.write(line)
+
+	hintWidth := 0
+	if b.Hinter != nil && b.Pos == len(b.Buf) {
+		if hint := b.Hinter(b.Buf); hint != "" {
+			rendered := ColorGrey + hint + ColorDefault
+			b.write(rendered)
+			hintWidth = visibleWidth([]byte(rendered))
+		}
+	}
+
+	if tail := (len(b.Buf) - b.Pos) + hintWidth; tail > 0 {
+		b.write(CursorLeftN(tail))
+	}
+}
+
+// Add inserts r at the cursor and redraws the remainder of the line.
+func (b *Buffer) Add(r rune) {
+	b.Buf = append(b.Buf, 0)
+	copy(b.Buf[b.Pos+1:], b.Buf[b.Pos:])
+	b.Buf[b.Pos] = r
+	b.Pos++
+
+	if b.decorated() {
+		b.redraw()
+		return
+	}
+
+	b.write(string(b.Buf[b.Pos-1:]))
+	if tail := len(b.Buf) - b.Pos; tail > 0 {
+		b.write(CursorLeftN(tail))
+	}
+}
+
+// Remove deletes the rune immediately before the cursor (backspace).
+func (b *Buffer) Remove() {
+	if b.Pos == 0 {
+		return
+	}
+
+	b.Buf = append(b.Buf[:b.Pos-1], b.Buf[b.Pos:]...)
+	b.Pos--
+
+	if b.decorated() {
+		b.redraw()
+		return
+	}
+
+	b.write(CursorLeftN(1))
+	b.write(string(b.Buf[b.Pos:]) + " " + ClearToEOL)
+	// Walk back over the tail plus the blank written over the old last rune.
+	b.write(CursorLeftN(len(b.Buf) - b.Pos + 1))
+}
+
+// Delete removes the rune at the cursor (forward delete).
+func (b *Buffer) Delete() {
+	if b.Pos >= len(b.Buf) {
+		return
+	}
+
+	b.Buf = append(b.Buf[:b.Pos], b.Buf[b.Pos+1:]...)
+
+	if b.decorated() {
+		b.redraw()
+		return
+	}
+
+	b.write(string(b.Buf[b.Pos:]) + ClearToEOL)
+	if tail := len(b.Buf) - b.Pos; tail > 0 {
+		b.write(CursorLeftN(tail))
+	}
+}
+
+// MoveLeft moves the cursor one rune left.
+func (b *Buffer) MoveLeft() {
+	if b.Pos == 0 {
+		return
+	}
+	b.Pos--
+
+	if b.decorated() {
+		b.redraw()
+		return
+	}
+
+	b.write(CursorLeftN(1))
+}
+
+// MoveRight moves the cursor one rune right.
+func (b *Buffer) MoveRight() {
+	if b.Pos >= len(b.Buf) {
+		return
+	}
+
+	if b.decorated() {
+		b.Pos++
+		b.redraw()
+		return
+	}
+
+	b.write(string(b.Buf[b.Pos]))
+	b.Pos++
+}
+
+// MoveToStart moves the cursor to the beginning of the line.
+func (b *Buffer) MoveToStart() {
+	if b.Pos == 0 {
+		return
+	}
+
+	if b.decorated() {
+		b.Pos = 0
+		b.redraw()
+		return
+	}
+
+	b.write(CursorLeftN(b.Pos))
+	b.Pos = 0
+}
+
+// MoveToEnd moves the cursor to the end of the line.
+func (b *Buffer) MoveToEnd() {
+	if b.Pos >= len(b.Buf) {
+		return
+	}
+
+	if b.decorated() {
+		b.Pos = len(b.Buf)
+		b.redraw()
+		return
+	}
+
+	b.write(string(b.Buf[b.Pos:]))
+	b.Pos = len(b.Buf)
+}
+
+// MoveLeftWord moves the cursor to the start of the previous word.
+func (b *Buffer) MoveLeftWord() {
+	start := b.wordLeft(b.Pos)
+
+	if b.decorated() {
+		b.Pos = start
+		b.redraw()
+		return
+	}
+
+	b.write(CursorLeftN(b.Pos - start))
+	b.Pos = start
+}
+
+// MoveRightWord moves the cursor to the start of the next word.
+func (b *Buffer) MoveRightWord() {
+	end := b.wordRight(b.Pos)
+
+	if b.decorated() {
+		b.Pos = end
+		b.redraw()
+		return
+	}
+
+	b.write(string(b.Buf[b.Pos:end]))
+	b.Pos = end
+}
+
+// DeleteWord deletes the word immediately before the cursor (Ctrl+W / Meta+Backspace).
+func (b *Buffer) DeleteWord() {
+	start := b.wordLeft(b.Pos)
+	if start == b.Pos {
+		return
+	}
+	oldPos := b.Pos
+
+	b.Buf = append(b.Buf[:start], b.Buf[b.Pos:]...)
+	b.Pos = start
+
+	if b.decorated() {
+		b.redraw()
+		return
+	}
+
+	b.write(CursorLeftN(oldPos - start))
+	b.write(string(b.Buf[b.Pos:]) + ClearToEOL)
+	if tail := len(b.Buf) - b.Pos; tail > 0 {
+		b.write(CursorLeftN(tail))
+	}
+}
+
+// DeleteRemaining deletes from the cursor to the end of the line (Ctrl+K).
+func (b *Buffer) DeleteRemaining() {
+	if b.Pos >= len(b.Buf) {
+		return
+	}
+	b.Buf = b.Buf[:b.Pos]
+
+	if b.decorated() {
+		b.redraw()
+		return
+	}
+
+	b.write(ClearToEOL)
+}
+
+// DeleteBefore deletes from the start of the line to the cursor (Ctrl+U).
+func (b *Buffer) DeleteBefore() {
+	if b.Pos == 0 {
+		return
+	}
+
+	oldPos := b.Pos
+	b.Buf = append([]rune{}, b.Buf[b.Pos:]...)
+	b.Pos = 0
+
+	if b.decorated() {
+		b.redraw()
+		return
+	}
+
+	b.write(CursorLeftN(oldPos))
+	b.write(string(b.Buf) + ClearToEOL)
+	if tail := len(b.Buf); tail > 0 {
+		b.write(CursorLeftN(tail))
+	}
+}
+
+// Replace swaps the buffer contents (e.g. for history navigation) and
+// redraws the line in place.
+func (b *Buffer) Replace(line []rune) {
+	oldPos := b.Pos
+
+	b.Buf = append([]rune{}, line...)
+	b.Pos = len(b.Buf)
+
+	if b.decorated() {
+		b.redraw()
+		return
+	}
+
+	if oldPos > 0 {
+		b.write(CursorLeftN(oldPos))
+	}
+	b.write(ClearToEOL)
+	b.write(string(b.Buf))
+}
+
+// ClearScreen clears the terminal and redraws the prompt and current line.
+func (b *Buffer) ClearScreen() {
+	b.write(ClearScreen + CursorReset)
+
+	if b.decorated() {
+		b.redraw()
+		return
+	}
+
+	if b.Prompt != nil {
+		b.write(b.Prompt.prompt())
+	}
+	b.write(string(b.Buf))
+	if tail := len(b.Buf) - b.Pos; tail > 0 {
+		b.write(CursorLeftN(tail))
+	}
+}
+
+// MoveTo moves the cursor directly to pos, redrawing as needed. Used by
+// vi-mode motions, which compute a target index rather than stepping one
+// rune at a time.
+func (b *Buffer) MoveTo(pos int) {
+	if pos < 0 {
+		pos = 0
+	}
+	if pos > len(b.Buf) {
+		pos = len(b.Buf)
+	}
+	oldPos := b.Pos
+	b.Pos = pos
+
+	if b.decorated() {
+		b.redraw()
+		return
+	}
+
+	switch {
+	case pos < oldPos:
+		b.write(CursorLeftN(oldPos - pos))
+	case pos > oldPos:
+		b.write(string(b.Buf[oldPos:pos]))
+	}
+}
+
+// DeleteRange removes the runes in [start, end), leaving the cursor at
+// start, and returns the deleted text so callers (vi operators) can stash
+// it in a register for later paste.
+func (b *Buffer) DeleteRange(start, end int) string {
+	if start < 0 {
+		start = 0
+	}
+	if end > len(b.Buf) {
+		end = len(b.Buf)
+	}
+	if start >= end {
+		return ""
+	}
+
+	deleted := string(b.Buf[start:end])
+	oldPos := b.Pos
+	b.Buf = append(b.Buf[:start], b.Buf[end:]...)
+	b.Pos = start
+
+	if b.decorated() {
+		b.redraw()
+		return deleted
+	}
+
+	// Bring the terminal cursor from oldPos to start; runes before start
+	// are unchanged, so they can be rewritten to move right.
+	switch {
+	case oldPos > start:
+		b.write(CursorLeftN(oldPos - start))
+	case oldPos < start:
+		b.write(string(b.Buf[oldPos:start]))
+	}
+	b.write(string(b.Buf[b.Pos:]) + ClearToEOL)
+	if tail := len(b.Buf) - b.Pos; tail > 0 {
+		b.write(CursorLeftN(tail))
+	}
+
+	return deleted
+}
+
+// InsertString inserts s at the cursor, leaving the cursor immediately
+// after the inserted text. Used by vi's p/P paste commands.
+func (b *Buffer) InsertString(s string) {
+	for _, r := range s {
+		b.Add(r)
+	}
+}
+
+// AcceptHint accepts the current Hinter suggestion, if any, appending it
+// to the buffer and reporting true. It's a no-op (returning false) unless
+// the cursor sits at the end of the line and a hint is showing there, so
+// callers can fall back to their normal Right-arrow/Ctrl+F behavior.
+func (b *Buffer) AcceptHint() bool {
+	if b.Hinter == nil || b.Pos != len(b.Buf) {
+		return false
+	}
+
+	hint := b.Hinter(b.Buf)
+	if hint == "" {
+		return false
+	}
+
+	b.InsertString(hint)
+	return true
+}
+
+// wordLeft returns the index of the start of the word to the left of pos.
+func (b *Buffer) wordLeft(pos int) int {
+	i := pos
+	for i > 0 && unicode.IsSpace(b.Buf[i-1]) {
+		i--
+	}
+	for i > 0 && !unicode.IsSpace(b.Buf[i-1]) {
+		i--
+	}
+	return i
+}
+
+// wordRight returns the index of the start of the next word at or after pos.
+func (b *Buffer) wordRight(pos int) int {
+	i := pos
+	for i < len(b.Buf) && !unicode.IsSpace(b.Buf[i]) {
+		i++
+	}
+	for i < len(b.Buf) && unicode.IsSpace(b.Buf[i]) {
+		i++
+	}
+	return i
+}
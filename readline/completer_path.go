@@ -0,0 +1,86 @@
+package readline
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PathCompleter completes filesystem paths for the token under the
+// cursor, expanding a leading "~" and respecting single- or
+// double-quoted arguments.
+type PathCompleter struct{}
+
+func (c *PathCompleter) Complete(line []rune, pos int) (head string, completions []string, tail string) {
+	s := string(line[:pos])
+	tail = string(line[pos:])
+
+	start := pathTokenStart(s)
+	head = s[:start]
+	token := s[start:]
+
+	var quote byte
+	if len(token) > 0 && (token[0] == '\'' || token[0] == '"') {
+		quote = token[0]
+		token = token[1:]
+	}
+
+	dir, prefix := filepath.Split(token)
+
+	searchDir := dir
+	if strings.HasPrefix(searchDir, "~") {
+		if home, err := os.UserHomeDir(); err == nil {
+			searchDir = home + strings.TrimPrefix(searchDir, "~")
+		}
+	}
+	if searchDir == "" {
+		searchDir = "."
+	}
+
+	entries, err := os.ReadDir(searchDir)
+	if err != nil {
+		return head, nil, tail
+	}
+
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		candidate := dir + name
+		if e.IsDir() {
+			candidate += "/"
+		}
+		if quote != 0 {
+			candidate = string(quote) + candidate
+		}
+		completions = append(completions, candidate)
+	}
+
+	return head, completions, tail
+}
+
+// pathTokenStart returns the index in s where the current (possibly
+// quoted) path token begins.
+func pathTokenStart(s string) int {
+	var inQuote byte
+	start := 0
+
+	for idx := 0; idx < len(s); idx++ {
+		c := s[idx]
+		switch {
+		case inQuote != 0:
+			if c == inQuote {
+				inQuote = 0
+			}
+		case c == '\'' || c == '"':
+			inQuote = c
+			start = idx
+		case c == ' ':
+			start = idx + 1
+		}
+	}
+
+	return start
+}
@@ -0,0 +1,41 @@
+package readline
+
+import "strings"
+
+// SlashCompleter completes the leading slash command of a line (e.g.
+// "/set", "/show", "/save", "/load", "/bye") as used by the ollama CLI.
+// It only offers completions for the command token itself; arguments
+// after the first space are left untouched.
+type SlashCompleter struct {
+	Commands []string
+}
+
+// NewSlashCompleter returns a SlashCompleter offering the given commands,
+// each of which should include its leading slash.
+func NewSlashCompleter(commands []string) *SlashCompleter {
+	return &SlashCompleter{Commands: commands}
+}
+
+func (c *SlashCompleter) Complete(line []rune, pos int) (head string, completions []string, tail string) {
+	s := string(line)
+	if !strings.HasPrefix(s, "/") {
+		return s[:pos], nil, s[pos:]
+	}
+
+	end := len(s)
+	if idx := strings.IndexByte(s, ' '); idx >= 0 {
+		end = idx
+	}
+	if pos > end {
+		return s[:pos], nil, s[pos:]
+	}
+
+	token := s[:end]
+	for _, cmd := range c.Commands {
+		if strings.HasPrefix(cmd, token) {
+			completions = append(completions, cmd)
+		}
+	}
+
+	return "", completions, s[end:]
+}
@@ -0,0 +1,116 @@
+package readline
+
+import "strings"
+
+// Completer provides tab-completion candidates for the token at pos in
+// line. head is everything that should stay to the left of the
+// completion, tail is everything that should stay to the right (so the
+// caller can splice head + completion + tail back into the buffer).
+type Completer interface {
+	Complete(line []rune, pos int) (head string, completions []string, tail string)
+}
+
+// tabState tracks the line as of the last Tab press, so a second
+// consecutive Tab with no edits in between lists completions instead of
+// re-completing the longest common prefix. pressed distinguishes "no Tab
+// pressed yet" from a legitimate empty-string line, since a Completer can
+// return multiple candidates for an empty buffer (e.g. listing "." when
+// the line is blank).
+type tabState struct {
+	line    string
+	pressed bool
+}
+
+// complete handles CharTab when i.Completer is configured: a single
+// candidate is inserted in place, multiple candidates complete the
+// longest common prefix, and a second consecutive Tab with no change to
+// the line lists the candidates in columns beneath the prompt.
+func (i *Instance) complete(buf *Buffer, tab *tabState) {
+	head, completions, tail := i.Completer.Complete(buf.Buf, buf.Pos)
+	if len(completions) == 0 {
+		i.Terminal.write("\a")
+		return
+	}
+
+	if len(completions) == 1 {
+		i.applyCompletion(buf, head, completions[0], tail)
+		*tab = tabState{line: buf.String(), pressed: true}
+		return
+	}
+
+	if tab.pressed && buf.String() == tab.line {
+		i.showCompletions(buf, completions)
+		return
+	}
+
+	if lcp := longestCommonPrefix(completions); lcp != "" {
+		i.applyCompletion(buf, head, lcp, tail)
+	}
+	*tab = tabState{line: buf.String(), pressed: true}
+}
+
+// applyCompletion splices completion between head and tail, leaving the
+// cursor immediately after the inserted completion.
+func (i *Instance) applyCompletion(buf *Buffer, head, completion, tail string) {
+	buf.Replace([]rune(head + completion + tail))
+	for range []rune(tail) {
+		buf.MoveLeft()
+	}
+}
+
+// showCompletions lists candidates in columns sized to the terminal
+// width, then redraws the prompt and current line beneath them.
+func (i *Instance) showCompletions(buf *Buffer, completions []string) {
+	width, _, ok := i.Terminal.Size()
+	if !ok || width <= 0 {
+		width = 80
+	}
+
+	colWidth := 0
+	for _, c := range completions {
+		if len(c) > colWidth {
+			colWidth = len(c)
+		}
+	}
+	colWidth += 2
+
+	cols := width / colWidth
+	if cols < 1 {
+		cols = 1
+	}
+
+	i.Terminal.write("\n")
+	for idx, c := range completions {
+		i.Terminal.write(c + strings.Repeat(" ", colWidth-len(c)))
+		if (idx+1)%cols == 0 {
+			i.Terminal.write("\n")
+		}
+	}
+	if len(completions)%cols != 0 {
+		i.Terminal.write("\n")
+	}
+
+	i.Terminal.write(i.Prompt.prompt() + buf.String())
+	if tail := len(buf.Buf) - buf.Pos; tail > 0 {
+		i.Terminal.write(CursorLeftN(tail))
+	}
+}
+
+// longestCommonPrefix returns the longest string that is a prefix of
+// every entry in ss.
+func longestCommonPrefix(ss []string) string {
+	if len(ss) == 0 {
+		return ""
+	}
+
+	prefix := ss[0]
+	for _, s := range ss[1:] {
+		for !strings.HasPrefix(s, prefix) {
+			prefix = prefix[:len(prefix)-1]
+			if prefix == "" {
+				return ""
+			}
+		}
+	}
+	return prefix
+}
@@ -0,0 +1,162 @@
+package readline
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestLongestCommonPrefix(t *testing.T) {
+	cases := []struct {
+		name string
+		in   []string
+		want string
+	}{
+		{name: "empty", in: nil, want: ""},
+		{name: "single", in: []string{"foo"}, want: "foo"},
+		{name: "shared prefix", in: []string{"foobar", "foobaz"}, want: "fooba"},
+		{name: "no shared prefix", in: []string{"foo", "bar"}, want: ""},
+		{name: "one is a prefix of the other", in: []string{"foo", "foobar"}, want: "foo"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := longestCommonPrefix(tc.in); got != tc.want {
+				t.Fatalf("longestCommonPrefix(%v) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSlashCompleterComplete(t *testing.T) {
+	c := NewSlashCompleter([]string{"/set", "/show", "/save", "/bye"})
+
+	t.Run("non-slash line gets no completions", func(t *testing.T) {
+		head, completions, tail := c.Complete([]rune("hello"), 5)
+		if completions != nil {
+			t.Fatalf("completions = %v, want nil", completions)
+		}
+		if head != "hello" || tail != "" {
+			t.Fatalf("head = %q, tail = %q, want %q, %q", head, tail, "hello", "")
+		}
+	})
+
+	t.Run("prefix matches the command token", func(t *testing.T) {
+		_, completions, tail := c.Complete([]rune("/s"), 2)
+		sort.Strings(completions)
+		want := []string{"/save", "/set", "/show"}
+		if len(completions) != len(want) {
+			t.Fatalf("completions = %v, want %v", completions, want)
+		}
+		for i, w := range want {
+			if completions[i] != w {
+				t.Fatalf("completions = %v, want %v", completions, want)
+			}
+		}
+		if tail != "" {
+			t.Fatalf("tail = %q, want empty", tail)
+		}
+	})
+
+	t.Run("cursor past the command token gets no completions", func(t *testing.T) {
+		_, completions, _ := c.Complete([]rune("/set foo"), 8)
+		if completions != nil {
+			t.Fatalf("completions = %v, want nil", completions)
+		}
+	})
+}
+
+func TestPathCompleterComplete(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"apple.txt", "banana.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "boxes"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &PathCompleter{}
+	prefix := dir + string(filepath.Separator) + "b"
+	line := []rune(prefix)
+
+	head, completions, tail := c.Complete(line, len(line))
+	if tail != "" {
+		t.Fatalf("tail = %q, want empty", tail)
+	}
+	if head != "" {
+		t.Fatalf("head = %q, want empty (whole line is one token)", head)
+	}
+
+	sort.Strings(completions)
+	want := []string{dir + string(filepath.Separator) + "banana.txt", dir + string(filepath.Separator) + "boxes/"}
+	sort.Strings(want)
+	if len(completions) != len(want) {
+		t.Fatalf("completions = %v, want %v", completions, want)
+	}
+	for i, w := range want {
+		if completions[i] != w {
+			t.Fatalf("completions = %v, want %v", completions, want)
+		}
+	}
+}
+
+// stubCompleter returns a fixed set of completions regardless of line/pos,
+// for exercising Instance.complete's tabState handling.
+type stubCompleter struct {
+	completions []string
+}
+
+func (c *stubCompleter) Complete(line []rune, pos int) (head string, completions []string, tail string) {
+	return "", c.completions, ""
+}
+
+func TestCompleteSingleCandidateAppliesInPlace(t *testing.T) {
+	i := &Instance{Completer: &stubCompleter{completions: []string{"hello"}}, Terminal: &Terminal{out: &bytes.Buffer{}}}
+	buf, err := NewBuffer(&Prompt{}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tab tabState
+	i.complete(buf, &tab)
+
+	if got := buf.String(); got != "hello" {
+		t.Fatalf("buf = %q, want %q", got, "hello")
+	}
+	if !tab.pressed || tab.line != "hello" {
+		t.Fatalf("tab = %+v, want pressed=true line=%q", tab, "hello")
+	}
+}
+
+func TestCompleteSecondTabListsCompletions(t *testing.T) {
+	var out bytes.Buffer
+	i := &Instance{
+		Completer: &stubCompleter{completions: []string{"foo", "bar"}},
+		Terminal:  &Terminal{out: &out},
+		Prompt:    &Prompt{},
+	}
+	buf, err := NewBuffer(i.Prompt, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tab tabState
+	i.complete(buf, &tab) // first Tab: no common prefix, so the line is unchanged
+	if got := buf.String(); got != "" {
+		t.Fatalf("buf after first Tab = %q, want empty", got)
+	}
+	if !tab.pressed {
+		t.Fatal("tab.pressed = false after first Tab, want true")
+	}
+
+	out.Reset()
+	i.complete(buf, &tab) // second Tab, line unchanged since: lists completions
+	listed := out.String()
+	if !bytes.Contains([]byte(listed), []byte("foo")) || !bytes.Contains([]byte(listed), []byte("bar")) {
+		t.Fatalf("showCompletions output = %q, want it to contain both candidates", listed)
+	}
+}
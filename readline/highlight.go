@@ -0,0 +1,64 @@
+package readline
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DefaultHighlighter colors slash commands, quoted strings, and
+// filesystem-looking paths in the current line, leaving everything else
+// as-is. It's a reasonable default for Instance.Highlighter; callers with
+// more specific syntax (e.g. model names, flags) can write their own.
+func DefaultHighlighter(line []rune, pos int) string {
+	var out strings.Builder
+
+	i := 0
+	for i < len(line) {
+		r := line[i]
+
+		switch {
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(line) && line[end] != r {
+				end++
+			}
+			if end < len(line) {
+				end++ // include the closing quote
+			}
+			out.WriteString(ColorGrey)
+			out.WriteString(string(line[i:end]))
+			out.WriteString(ColorDefault)
+			i = end
+
+		case unicode.IsSpace(r):
+			out.WriteRune(r)
+			i++
+
+		default:
+			start := i
+			for i < len(line) && !unicode.IsSpace(line[i]) && line[i] != '"' && line[i] != '\'' {
+				i++
+			}
+			word := string(line[start:i])
+			if isHighlightableWord(word) {
+				out.WriteString(ColorGrey)
+				out.WriteString(word)
+				out.WriteString(ColorDefault)
+			} else {
+				out.WriteString(word)
+			}
+		}
+	}
+
+	return out.String()
+}
+
+// isHighlightableWord reports whether word is a /slash command or a
+// relative/home-relative filesystem path, the two unquoted cases
+// DefaultHighlighter colors.
+func isHighlightableWord(word string) bool {
+	return strings.HasPrefix(word, "/") ||
+		strings.HasPrefix(word, "./") ||
+		strings.HasPrefix(word, "../") ||
+		strings.HasPrefix(word, "~/")
+}
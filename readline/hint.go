@@ -0,0 +1,26 @@
+package readline
+
+import "strings"
+
+// NewHistoryHinter returns a Hinter that suggests the remainder of the
+// most recent history entry prefixed by the current line, giving a live
+// preview of what was typed last time (fish/zsh-autosuggestions style).
+// It does a direct scan of h.Lines rather than using History.Search,
+// since Search matches query as a substring anywhere in the line and a
+// hint needs a true prefix match.
+func NewHistoryHinter(h *History) func(line []rune) string {
+	return func(line []rune) string {
+		if len(line) == 0 {
+			return ""
+		}
+
+		prefix := string(line)
+		for idx := len(h.Lines) - 1; idx >= 0; idx-- {
+			entry := h.Lines[idx]
+			if entry != prefix && strings.HasPrefix(entry, prefix) {
+				return entry[len(prefix):]
+			}
+		}
+		return ""
+	}
+}
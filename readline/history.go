@@ -0,0 +1,212 @@
+package readline
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// HistoryOptions configures a file-backed History created with
+// NewHistoryFile.
+type HistoryOptions struct {
+	// MaxEntries caps the number of lines kept, both in memory and on
+	// disk. Zero means unlimited.
+	MaxEntries int
+
+	// Dedup collapses consecutive duplicate entries and, on Add, moves a
+	// repeated entry to the end instead of appending a second copy.
+	Dedup bool
+
+	// IgnorePrefix skips adding (and loading) lines that start with this
+	// prefix, e.g. a leading space for "don't record this" commands.
+	IgnorePrefix string
+}
+
+// History holds the in-memory list of previously submitted lines and the
+// cursor used to walk it with Ctrl+P/Ctrl+N (or the up/down arrows). It
+// is optionally backed by a file on disk.
+type History struct {
+	Enabled bool
+	Lines   []string
+	Pos     int
+
+	path string
+	opts HistoryOptions
+}
+
+// NewHistory creates an empty, enabled, in-memory-only History.
+func NewHistory() (*History, error) {
+	return &History{
+		Enabled: true,
+		Lines:   make([]string, 0, 100),
+	}, nil
+}
+
+// NewHistoryFile creates an enabled History backed by path, loading any
+// existing lines (most recent MaxEntries kept) and persisting every
+// subsequent Add via an atomic rewrite of the file.
+func NewHistoryFile(path string, opts HistoryOptions) (*History, error) {
+	h := &History{
+		Enabled: true,
+		Lines:   make([]string, 0, 100),
+		path:    path,
+		opts:    opts,
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			h.Pos = 0
+			return h, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if h.opts.IgnorePrefix != "" && strings.HasPrefix(line, h.opts.IgnorePrefix) {
+			continue
+		}
+		h.appendLine(line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	h.truncate()
+	h.Pos = len(h.Lines)
+
+	return h, nil
+}
+
+// Add appends line to the history, resets Pos to the end, and - if the
+// History is file-backed - persists the change, unless history is
+// disabled, the line is blank, or it matches IgnorePrefix.
+func (h *History) Add(line string) {
+	if !h.Enabled || line == "" {
+		return
+	}
+	if h.opts.IgnorePrefix != "" && strings.HasPrefix(line, h.opts.IgnorePrefix) {
+		return
+	}
+
+	h.appendLine(line)
+	h.truncate()
+	h.Pos = len(h.Lines)
+
+	if h.path != "" {
+		//nolint:errcheck
+		h.save()
+	}
+}
+
+// appendLine adds line to Lines, honoring Dedup by moving a repeated
+// entry to the end rather than appending a second copy.
+func (h *History) appendLine(line string) {
+	if h.opts.Dedup {
+		for idx, existing := range h.Lines {
+			if existing == line {
+				h.Lines = append(h.Lines[:idx], h.Lines[idx+1:]...)
+				break
+			}
+		}
+	}
+
+	h.Lines = append(h.Lines, line)
+}
+
+// truncate drops the oldest entries beyond MaxEntries.
+func (h *History) truncate() {
+	if h.opts.MaxEntries <= 0 || len(h.Lines) <= h.opts.MaxEntries {
+		return
+	}
+	h.Lines = h.Lines[len(h.Lines)-h.opts.MaxEntries:]
+}
+
+// save atomically rewrites the history file: it writes the current
+// Lines to a temp file in the same directory and renames it over path,
+// so a concurrent ollama session never observes a partial write.
+func (h *History) save() error {
+	dir := filepath.Dir(h.path)
+	tmp, err := os.CreateTemp(dir, ".history-*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+
+	w := bufio.NewWriter(tmp)
+	for _, line := range h.Lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			tmp.Close()
+			os.Remove(tmpName)
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+
+	return os.Rename(tmpName, h.path)
+}
+
+// Size returns the number of entries in the history.
+func (h *History) Size() int {
+	return len(h.Lines)
+}
+
+// Prev moves Pos one entry back and returns the entry there.
+func (h *History) Prev() string {
+	if h.Pos <= 0 {
+		return ""
+	}
+	h.Pos--
+	return h.Lines[h.Pos]
+}
+
+// Next moves Pos one entry forward and returns the entry there, or ""
+// once Pos reaches the end of the history.
+func (h *History) Next() string {
+	if h.Pos >= len(h.Lines) {
+		return ""
+	}
+	h.Pos++
+	if h.Pos == len(h.Lines) {
+		return ""
+	}
+	return h.Lines[h.Pos]
+}
+
+// Search scans Lines for the nearest entry containing query, starting
+// just before (backward) or after (forward) index from. It reports the
+// index and line of the match, or ok=false if none was found.
+func (h *History) Search(query string, backward bool, from int) (idx int, line string, ok bool) {
+	if query == "" {
+		return 0, "", false
+	}
+
+	if backward {
+		for i := from - 1; i >= 0; i-- {
+			if strings.Contains(h.Lines[i], query) {
+				return i, h.Lines[i], true
+			}
+		}
+		return 0, "", false
+	}
+
+	for i := from + 1; i < len(h.Lines); i++ {
+		if strings.Contains(h.Lines[i], query) {
+			return i, h.Lines[i], true
+		}
+	}
+	return 0, "", false
+}
@@ -0,0 +1,147 @@
+package readline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHistoryPrevNext(t *testing.T) {
+	h, err := NewHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h.Add("one")
+	h.Add("two")
+	h.Add("three")
+
+	if got := h.Prev(); got != "three" {
+		t.Fatalf("Prev() = %q, want %q", got, "three")
+	}
+	if got := h.Prev(); got != "two" {
+		t.Fatalf("Prev() = %q, want %q", got, "two")
+	}
+	if got := h.Next(); got != "three" {
+		t.Fatalf("Next() = %q, want %q", got, "three")
+	}
+	if got := h.Next(); got != "" {
+		t.Fatalf("Next() at end = %q, want empty", got)
+	}
+}
+
+func TestHistoryDedupMovesToEnd(t *testing.T) {
+	h, err := NewHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.opts.Dedup = true
+
+	h.Add("a")
+	h.Add("b")
+	h.Add("a")
+
+	want := []string{"b", "a"}
+	if len(h.Lines) != len(want) {
+		t.Fatalf("Lines = %v, want %v", h.Lines, want)
+	}
+	for idx, line := range want {
+		if h.Lines[idx] != line {
+			t.Fatalf("Lines = %v, want %v", h.Lines, want)
+		}
+	}
+}
+
+func TestHistoryTruncate(t *testing.T) {
+	h, err := NewHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.opts.MaxEntries = 2
+
+	h.Add("a")
+	h.Add("b")
+	h.Add("c")
+
+	want := []string{"b", "c"}
+	if len(h.Lines) != len(want) || h.Lines[0] != want[0] || h.Lines[1] != want[1] {
+		t.Fatalf("Lines = %v, want %v", h.Lines, want)
+	}
+}
+
+func TestHistoryFileIgnorePrefixOnLoadAndAdd(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "history")
+
+	if err := os.WriteFile(path, []byte(" secret\nkeep-me\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := NewHistoryFile(path, HistoryOptions{IgnorePrefix: " "})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(h.Lines) != 1 || h.Lines[0] != "keep-me" {
+		t.Fatalf("Lines = %v, want [keep-me]", h.Lines)
+	}
+
+	h.Add(" also-secret")
+	if len(h.Lines) != 1 {
+		t.Fatalf("Add with IgnorePrefix changed Lines: %v", h.Lines)
+	}
+}
+
+func TestHistoryFileSaveIsAtomicAndReloadable(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "history")
+
+	h, err := NewHistoryFile(path, HistoryOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Add("one")
+	h.Add("two")
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if e.Name() != "history" {
+			t.Fatalf("stray temp file left behind: %s", e.Name())
+		}
+	}
+
+	reloaded, err := NewHistoryFile(path, HistoryOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reloaded.Lines) != 2 || reloaded.Lines[0] != "one" || reloaded.Lines[1] != "two" {
+		t.Fatalf("reloaded Lines = %v, want [one two]", reloaded.Lines)
+	}
+}
+
+func TestHistorySearch(t *testing.T) {
+	h, err := NewHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Add("ollama run llama3")
+	h.Add("ollama pull mistral")
+	h.Add("ls -la")
+
+	idx, line, ok := h.Search("ollama", true, len(h.Lines))
+	if !ok || idx != 1 || line != "ollama pull mistral" {
+		t.Fatalf("Search backward = (%d, %q, %v), want (1, %q, true)", idx, line, ok, "ollama pull mistral")
+	}
+
+	idx, line, ok = h.Search("ollama", true, idx)
+	if !ok || idx != 0 || line != "ollama run llama3" {
+		t.Fatalf("Search backward continued = (%d, %q, %v), want (0, %q, true)", idx, line, ok, "ollama run llama3")
+	}
+
+	if _, _, ok := h.Search("ollama", true, idx); ok {
+		t.Fatalf("Search backward past the oldest match should fail")
+	}
+}
@@ -0,0 +1,46 @@
+package readline
+
+// KeyAction handles a single key read while in insert/emacs dispatch,
+// given the Instance (for History, Terminal, etc.) and the active Buffer.
+type KeyAction func(i *Instance, buf *Buffer)
+
+// Keymap maps a key rune to the action it runs. Instance.Readline consults
+// it before falling through to the built-in switch, so callers (and
+// EditMode ModeVi's insert mode) can override or extend single-key
+// bindings without forking the read loop.
+type Keymap map[rune]KeyAction
+
+// clone returns a copy of m, so each Instance can customize its own
+// bindings without mutating the shared default.
+func (m Keymap) clone() Keymap {
+	c := make(Keymap, len(m))
+	for r, action := range m {
+		c[r] = action
+	}
+	return c
+}
+
+// moveRightOrAcceptHint advances the cursor, unless a Hinter suggestion is
+// showing at the end of the line, in which case it accepts the hint
+// instead.
+func moveRightOrAcceptHint(_ *Instance, buf *Buffer) {
+	if !buf.AcceptHint() {
+		buf.MoveRight()
+	}
+}
+
+// defaultEmacsKeymap holds the single-key bindings shared by both emacs
+// mode and vi insert mode: cursor movement and line editing that aren't
+// specific to either mode.
+var defaultEmacsKeymap = Keymap{
+	CharLineStart: func(_ *Instance, buf *Buffer) { buf.MoveToStart() },
+	CharLineEnd:   func(_ *Instance, buf *Buffer) { buf.MoveToEnd() },
+	CharBackward:  func(_ *Instance, buf *Buffer) { buf.MoveLeft() },
+	CharForward:   moveRightOrAcceptHint,
+	CharBackspace: func(_ *Instance, buf *Buffer) { buf.Remove() },
+	CharCtrlH:     func(_ *Instance, buf *Buffer) { buf.Remove() },
+	CharKill:      func(_ *Instance, buf *Buffer) { buf.DeleteRemaining() },
+	CharCtrlU:     func(_ *Instance, buf *Buffer) { buf.DeleteBefore() },
+	CharCtrlL:     func(_ *Instance, buf *Buffer) { buf.ClearScreen() },
+	CharCtrlW:     func(_ *Instance, buf *Buffer) { buf.DeleteWord() },
+}
@@ -16,6 +16,11 @@ type Prompt struct {
 	Placeholder    string
 	AltPlaceholder string
 	UseAlt         bool
+
+	// ModeIndicator, if set, renders a short status (e.g. "[INSERT]") for
+	// the current vi editing mode. It is only consulted when
+	// Instance.EditMode is ModeVi.
+	ModeIndicator func(insert bool) string
 }
 
 func (p *Prompt) prompt() string {
@@ -33,17 +38,67 @@ func (p *Prompt) placeholder() string {
 }
 
 type Terminal struct {
-	reader  *bufio.Reader
-	rawmode bool
-	termios any
+	reader *bufio.Reader
+	out    io.Writer
+
+	// managesRawMode is false for a Terminal built over an arbitrary
+	// io.Reader/io.Writer (see NewTerminalIO), since there is no local
+	// tty fd for Readline to put into raw mode.
+	managesRawMode bool
+	rawmode        bool
+	termios        any
+
+	// sizeFn, if set, overrides Size's default of querying the local
+	// tty - used by readline/remote to report the client's terminal size
+	// instead of the server's own.
+	sizeFn func() (cols, rows int, ok bool)
+}
+
+// Size returns the terminal's current column and row count. A local
+// Terminal (see NewTerminal) queries the tty directly; a Terminal built
+// with NewTerminalIO has no tty of its own and reports ok=false unless a
+// caller has installed a size source with SetSizeFn.
+func (t *Terminal) Size() (cols, rows int, ok bool) {
+	if t.sizeFn != nil {
+		return t.sizeFn()
+	}
+	if !t.managesRawMode {
+		return 0, 0, false
+	}
+	cols, rows, err := term.GetSize(int(os.Stdout.Fd()))
+	return cols, rows, err == nil
+}
+
+// SetSizeFn installs fn as t's size source, overriding the default of
+// querying the local tty; used by readline/remote to report the client's
+// terminal size instead of the server's own.
+func (t *Terminal) SetSizeFn(fn func() (cols, rows int, ok bool)) {
+	t.sizeFn = fn
+}
+
+// write renders s to the terminal's output, which is os.Stdout for a
+// local Terminal but may be redirected (e.g. by readline/remote) to
+// stream rendered bytes to a remote client instead.
+func (t *Terminal) write(s string) {
+	fmt.Fprint(t.out, s)
 }
 
 type Instance struct {
 	Prompt     *Prompt
 	Terminal   *Terminal
 	History    *History
+	Completer  Completer
+	EditMode   EditMode
+	Keymap     Keymap
 	Pasting    bool
 	ToolOutput string // Last tool output for Ctrl+O expansion
+
+	// Highlighter and Hinter customize how the current line is rendered;
+	// see Buffer's fields of the same name. Both are optional.
+	Highlighter func(line []rune, pos int) string
+	Hinter      func(line []rune) string
+
+	vi *viState
 }
 
 func New(prompt Prompt) (*Instance, error) {
@@ -61,11 +116,29 @@ func New(prompt Prompt) (*Instance, error) {
 		Prompt:   &prompt,
 		Terminal: term,
 		History:  history,
+		Keymap:   defaultEmacsKeymap.clone(),
+	}, nil
+}
+
+// NewWithTerminal builds an Instance the same way New does, but over a
+// caller-supplied Terminal rather than the local tty - used by
+// readline/remote to host a Readline session over a network connection.
+func NewWithTerminal(prompt Prompt, term *Terminal) (*Instance, error) {
+	history, err := NewHistory()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Instance{
+		Prompt:   &prompt,
+		Terminal: term,
+		History:  history,
+		Keymap:   defaultEmacsKeymap.clone(),
 	}, nil
 }
 
 func (i *Instance) Readline() (string, error) {
-	if !i.Terminal.rawmode {
+	if i.Terminal.managesRawMode && !i.Terminal.rawmode {
 		fd := os.Stdin.Fd()
 		termios, err := SetRawMode(fd)
 		if err != nil {
@@ -80,41 +153,69 @@ func (i *Instance) Readline() (string, error) {
 		// force alt prompt when pasting
 		prompt = i.Prompt.AltPrompt
 	}
-	fmt.Print(prompt)
+	i.Terminal.write(prompt)
 
-	defer func() {
-		fd := os.Stdin.Fd()
-		//nolint:errcheck
-		UnsetRawMode(fd, i.Terminal.termios)
-		i.Terminal.rawmode = false
-	}()
+	if i.Terminal.managesRawMode {
+		defer func() {
+			fd := os.Stdin.Fd()
+			//nolint:errcheck
+			UnsetRawMode(fd, i.Terminal.termios)
+			i.Terminal.rawmode = false
+		}()
+	}
 
-	buf, _ := NewBuffer(i.Prompt)
+	buf, _ := NewBuffer(i.Prompt, i.Terminal.out)
+	buf.Highlighter = i.Highlighter
+	buf.Hinter = i.Hinter
+
+	if i.EditMode == ModeVi {
+		if i.vi == nil {
+			i.vi = newViState()
+		} else {
+			i.vi.insert = true
+			i.vi.pending = 0
+			i.vi.opCount = 0
+			i.vi.hasUndo = false
+			i.vi.lastCmd = nil
+			i.vi.insertSetup = nil
+		}
+	}
 
 	var esc bool
 	var escex bool
 	var metaDel bool
 
 	var currentLineBuf []rune
+	var lastTab tabState
 
 	for {
 		// don't show placeholder when pasting unless we're in multiline mode
 		showPlaceholder := !i.Pasting || i.Prompt.UseAlt
 		if buf.IsEmpty() && showPlaceholder {
 			ph := i.Prompt.placeholder()
-			fmt.Print(ColorGrey + ph + CursorLeftN(len(ph)) + ColorDefault)
+			i.Terminal.write(ColorGrey + ph + CursorLeftN(visibleWidth([]byte(ph))) + ColorDefault)
+		}
+
+		if i.EditMode == ModeVi && i.Prompt.ModeIndicator != nil {
+			if indicator := i.Prompt.ModeIndicator(i.vi.insert); indicator != "" {
+				i.Terminal.write(ColorGrey + indicator + CursorLeftN(visibleWidth([]byte(indicator))) + ColorDefault)
+			}
 		}
 
 		r, err := i.Terminal.Read()
 
 		if buf.IsEmpty() {
-			fmt.Print(ClearToEOL)
+			i.Terminal.write(ClearToEOL)
 		}
 
 		if err != nil {
 			return "", io.EOF
 		}
 
+		if r != CharTab {
+			lastTab = tabState{}
+		}
+
 		if escex {
 			escex = false
 
@@ -126,7 +227,9 @@ func (i *Instance) Readline() (string, error) {
 			case KeyLeft:
 				buf.MoveLeft()
 			case KeyRight:
-				buf.MoveRight()
+				if !buf.AcceptHint() {
+					buf.MoveRight()
+				}
 			case CharBracketedPaste:
 				var code string
 				for range 3 {
@@ -159,6 +262,27 @@ func (i *Instance) Readline() (string, error) {
 		} else if esc {
 			esc = false
 
+			if i.EditMode == ModeVi {
+				if r == CharEscapeEx {
+					escex = true
+					continue
+				}
+
+				// A lone Esc (not the start of an arrow/function key
+				// sequence) exits insert mode; dispatch r itself as the
+				// first normal-mode command rather than discarding it.
+				i.vi.insert = false
+				i.viEndInsert(buf)
+				output, done, verr := i.viNormal(buf, r)
+				if verr != nil {
+					return "", verr
+				}
+				if done {
+					return output, nil
+				}
+				continue
+			}
+
 			switch r {
 			case 'b':
 				buf.MoveLeftWord()
@@ -172,58 +296,77 @@ func (i *Instance) Readline() (string, error) {
 			continue
 		}
 
+		if r == CharEsc {
+			esc = true
+			continue
+		}
+
+		if i.EditMode == ModeVi && !i.vi.insert {
+			output, done, verr := i.viNormal(buf, r)
+			if verr != nil {
+				return "", verr
+			}
+			if done {
+				return output, nil
+			}
+			continue
+		}
+
+		if action, ok := i.Keymap[r]; ok {
+			action(i, buf)
+			continue
+		}
+
 		switch r {
 		case CharNull:
 			continue
-		case CharEsc:
-			esc = true
 		case CharInterrupt:
 			return "", ErrInterrupt
 		case CharPrev:
 			i.historyPrev(buf, &currentLineBuf)
 		case CharNext:
 			i.historyNext(buf, &currentLineBuf)
-		case CharLineStart:
-			buf.MoveToStart()
-		case CharLineEnd:
-			buf.MoveToEnd()
-		case CharBackward:
-			buf.MoveLeft()
-		case CharForward:
-			buf.MoveRight()
-		case CharBackspace, CharCtrlH:
-			buf.Remove()
+		case CharCtrlR:
+			output, ok, err := i.reverseSearch(buf)
+			if err != nil {
+				return "", err
+			}
+			if ok {
+				buf.MoveToEnd()
+				i.Terminal.write("\n")
+				return output, nil
+			}
+			i.Terminal.write(prompt + buf.String())
 		case CharTab:
-			// todo: convert back to real tabs
-			for range 8 {
-				buf.Add(' ')
+			if i.Completer == nil {
+				// todo: convert back to real tabs
+				for range 8 {
+					buf.Add(' ')
+				}
+				break
 			}
+			i.complete(buf, &lastTab)
 		case CharDelete:
 			if buf.DisplaySize() > 0 {
 				buf.Delete()
 			} else {
 				return "", io.EOF
 			}
-		case CharKill:
-			buf.DeleteRemaining()
-		case CharCtrlU:
-			buf.DeleteBefore()
-		case CharCtrlL:
-			buf.ClearScreen()
 		case CharCtrlO:
 			// Ctrl+O - show tool output in pager
 			if i.ToolOutput == "" {
 				// No output to show, just beep
-				fmt.Print("\a")
+				i.Terminal.write("\a")
 				continue
 			}
 
 			// Show pager in alternate screen (original view restored on exit)
-			showPager(i.ToolOutput)
+			showPager(i.Terminal, i.ToolOutput)
 			continue
-		case CharCtrlW:
-			buf.DeleteWord()
 		case CharCtrlZ:
+			if !i.Terminal.managesRawMode {
+				continue
+			}
 			fd := os.Stdin.Fd()
 			return handleCharCtrlZ(fd, i.Terminal.termios)
 		case CharEnter, CharCtrlJ:
@@ -232,7 +375,7 @@ func (i *Instance) Readline() (string, error) {
 				i.History.Add(output)
 			}
 			buf.MoveToEnd()
-			fmt.Println()
+			i.Terminal.write("\n")
 
 			return output, nil
 		default:
@@ -249,6 +392,10 @@ func (i *Instance) Readline() (string, error) {
 
 // SetRawMode enables raw mode to prevent terminal from interpreting control chars
 func (i *Instance) SetRawMode(on bool) {
+	if !i.Terminal.managesRawMode {
+		return
+	}
+
 	fd := os.Stdin.Fd()
 	if on && !i.Terminal.rawmode {
 		termios, err := SetRawMode(fd)
@@ -270,6 +417,12 @@ func (i *Instance) HistoryDisable() {
 	i.History.Enabled = false
 }
 
+// HistoryList returns a snapshot of the current history entries, oldest
+// first, for callers like the REPL's /history command.
+func (i *Instance) HistoryList() []string {
+	return append([]string(nil), i.History.Lines...)
+}
+
 func (i *Instance) historyPrev(buf *Buffer, currentLineBuf *[]rune) {
 	if i.History.Pos > 0 {
 		if i.History.Pos == i.History.Size() {
@@ -299,12 +452,27 @@ func NewTerminal() (*Terminal, error) {
 	}
 
 	t := &Terminal{
-		reader: bufio.NewReader(os.Stdin),
+		reader:         bufio.NewReader(os.Stdin),
+		out:            os.Stdout,
+		managesRawMode: true,
 	}
 
 	return t, nil
 }
 
+// NewTerminalIO builds a Terminal that reads and writes through r and w
+// instead of the local os.Stdin/os.Stdout, for callers (such as
+// readline/remote) that multiplex a Readline session over a connection.
+// Raw-mode handling is the caller's responsibility in that case (the
+// remote client puts its own local tty into raw mode), so Readline does
+// not attempt to manage raw mode on the returned Terminal.
+func NewTerminalIO(r io.Reader, w io.Writer) *Terminal {
+	return &Terminal{
+		reader: bufio.NewReader(r),
+		out:    w,
+	}
+}
+
 func (t *Terminal) Read() (rune, error) {
 	r, _, err := t.reader.ReadRune()
 	if err != nil {
@@ -313,26 +481,26 @@ func (t *Terminal) Read() (rune, error) {
 	return r, nil
 }
 
-// showPager displays content in a simple pager that exits on 'q' or Ctrl+O
-func showPager(content string) {
+// showPager displays content in a simple pager that exits on 'q' or
+// Ctrl+O. It renders through t so the pager works over a remote Terminal
+// (see readline/remote) as well as the local tty.
+func showPager(t *Terminal, content string) {
 	lines := strings.Split(content, "\n")
 	offset := 0
 
 	// Get terminal size (default to 80x24 if we can't determine)
 	termWidth, termHeight := 80, 24
-	if w, h, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+	if w, h, ok := t.Size(); ok {
 		termWidth, termHeight = w, h-1 // Leave room for status line
 	}
 
 	// Enter alternate screen buffer (preserves chat history)
-	fmt.Print(EnterAltScreen)
-	defer fmt.Print(ExitAltScreen)
-
-	reader := bufio.NewReader(os.Stdin)
+	t.write(EnterAltScreen)
+	defer t.write(ExitAltScreen)
 
 	for {
 		// Clear screen and move cursor to top
-		fmt.Print(ClearScreen + CursorReset)
+		t.write(ClearScreen + CursorReset)
 
 		// Display visible lines
 		end := offset + termHeight
@@ -344,14 +512,14 @@ func showPager(content string) {
 			if len(line) > termWidth {
 				line = line[:termWidth]
 			}
-			fmt.Println(line)
+			t.write(line + "\r\n")
 		}
 
 		// Show status line
-		fmt.Printf(ColorGrey+"[Lines %d-%d of %d] Press q or Ctrl+O to exit, j/k or arrows to scroll"+ColorDefault, offset+1, end, len(lines))
+		t.write(fmt.Sprintf(ColorGrey+"[Lines %d-%d of %d] Press q or Ctrl+O to exit, j/k or arrows to scroll"+ColorDefault, offset+1, end, len(lines)))
 
 		// Read input
-		r, _, err := reader.ReadRune()
+		r, err := t.Read()
 		if err != nil {
 			return
 		}
@@ -389,9 +557,9 @@ func showPager(content string) {
 			}
 		case CharEsc:
 			// Handle escape sequences for arrow keys
-			r2, _, _ := reader.ReadRune()
+			r2, _ := t.Read()
 			if r2 == '[' {
-				r3, _, _ := reader.ReadRune()
+				r3, _ := t.Read()
 				switch r3 {
 				case 'A': // Up
 					if offset > 0 {
@@ -402,13 +570,13 @@ func showPager(content string) {
 						offset++
 					}
 				case '5': // Page up
-					reader.ReadRune() // consume ~
+					t.Read() // consume ~
 					offset -= termHeight
 					if offset < 0 {
 						offset = 0
 					}
 				case '6': // Page down
-					reader.ReadRune() // consume ~
+					t.Read() // consume ~
 					offset += termHeight
 					if offset > len(lines)-termHeight {
 						offset = len(lines) - termHeight
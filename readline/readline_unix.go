@@ -0,0 +1,37 @@
+//go:build !windows
+
+package readline
+
+import (
+	"syscall"
+
+	"golang.org/x/term"
+)
+
+// SetRawMode puts fd into raw mode and returns the previous state so it can
+// be restored with UnsetRawMode.
+func SetRawMode(fd uintptr) (any, error) {
+	return term.MakeRaw(int(fd))
+}
+
+// UnsetRawMode restores the terminal state captured by SetRawMode.
+func UnsetRawMode(fd uintptr, state any) error {
+	return term.Restore(int(fd), state.(*term.State))
+}
+
+// handleCharCtrlZ suspends the process (as the shell would on Ctrl+Z),
+// restoring the terminal first and re-entering raw mode on resume.
+func handleCharCtrlZ(fd uintptr, state any) (string, error) {
+	if err := UnsetRawMode(fd, state); err != nil {
+		return "", err
+	}
+
+	//nolint:errcheck
+	syscall.Kill(syscall.Getpid(), syscall.SIGTSTP)
+
+	if _, err := SetRawMode(fd); err != nil {
+		return "", err
+	}
+
+	return "", nil
+}
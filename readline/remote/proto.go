@@ -0,0 +1,117 @@
+package remote
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// maxFrameSize bounds a single frame's payload. Connections aren't
+// necessarily localhost-trusted (remote.Serve takes a net.Listener a thin
+// client can dial), so readFrame must reject an oversized length prefix
+// before allocating for it rather than trusting whatever a peer sends.
+const maxFrameSize = 1 << 20 // 1 MiB, comfortably above a pasted line or a pager page
+
+// ErrFrameTooLarge is returned by readFrame when a peer's length prefix
+// exceeds maxFrameSize.
+var ErrFrameTooLarge = errors.New("remote: frame exceeds maximum size")
+
+// frameType identifies the kind of payload carried by a frame on the
+// wire. Frames are multiplexed over a single net.Conn so that raw
+// keystrokes, rendered output, resize events and mode changes can share
+// one connection.
+type frameType byte
+
+const (
+	// frameInput carries raw bytes typed by the client, client -> server.
+	frameInput frameType = iota + 1
+	// frameOutput carries rendered bytes to display, server -> client.
+	frameOutput
+	// frameResize carries a terminal resize event, client -> server.
+	frameResize
+	// frameMode carries a terminal mode change (e.g. bracketed paste,
+	// on/off), server -> client.
+	frameMode
+)
+
+// modeKind identifies which terminal mode a frameMode frame toggles. It
+// is a byte rather than a bool payload so additional modes (e.g. mouse
+// reporting) can be added without a new frameType.
+type modeKind byte
+
+const (
+	modeBracketedPaste modeKind = iota
+)
+
+// writeFrame writes a length-prefixed frame: 1 byte type, 4 byte
+// big-endian payload length, then the payload.
+func writeFrame(w io.Writer, t frameType, payload []byte) error {
+	header := make([]byte, 5)
+	header[0] = byte(t)
+	binary.BigEndian.PutUint32(header[1:], uint32(len(payload)))
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if len(payload) == 0 {
+		return nil
+	}
+	_, err := w.Write(payload)
+	return err
+}
+
+// readFrame reads one length-prefixed frame written by writeFrame.
+func readFrame(r io.Reader) (frameType, []byte, error) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+
+	t := frameType(header[0])
+	n := binary.BigEndian.Uint32(header[1:])
+	if n > maxFrameSize {
+		return 0, nil, ErrFrameTooLarge
+	}
+
+	payload := make([]byte, n)
+	if n > 0 {
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	return t, payload, nil
+}
+
+// resizePayload encodes a terminal size as a 4-byte frameResize payload.
+func resizePayload(rows, cols int) []byte {
+	payload := make([]byte, 4)
+	binary.BigEndian.PutUint16(payload[0:], uint16(rows))
+	binary.BigEndian.PutUint16(payload[2:], uint16(cols))
+	return payload
+}
+
+// decodeResize decodes a frameResize payload written by resizePayload.
+func decodeResize(payload []byte) (rows, cols int) {
+	if len(payload) < 4 {
+		return 0, 0
+	}
+	return int(binary.BigEndian.Uint16(payload[0:])), int(binary.BigEndian.Uint16(payload[2:]))
+}
+
+// modePayload encodes a mode change as a 2-byte frameMode payload.
+func modePayload(kind modeKind, on bool) []byte {
+	state := byte(0)
+	if on {
+		state = 1
+	}
+	return []byte{byte(kind), state}
+}
+
+// decodeMode decodes a frameMode payload written by modePayload.
+func decodeMode(payload []byte) (kind modeKind, on bool) {
+	if len(payload) < 2 {
+		return 0, false
+	}
+	return modeKind(payload[0]), payload[1] != 0
+}
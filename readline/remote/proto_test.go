@@ -0,0 +1,84 @@
+package remote
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestWriteReadFrameRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := writeFrame(&buf, frameInput, []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+
+	gotType, payload, err := readFrame(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotType != frameInput {
+		t.Fatalf("type = %v, want %v", gotType, frameInput)
+	}
+	if string(payload) != "hello" {
+		t.Fatalf("payload = %q, want %q", payload, "hello")
+	}
+}
+
+func TestWriteReadFrameEmptyPayload(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := writeFrame(&buf, frameOutput, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	gotType, payload, err := readFrame(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotType != frameOutput {
+		t.Fatalf("type = %v, want %v", gotType, frameOutput)
+	}
+	if len(payload) != 0 {
+		t.Fatalf("payload = %q, want empty", payload)
+	}
+}
+
+func TestReadFrameRejectsOversizedLength(t *testing.T) {
+	var buf bytes.Buffer
+
+	header := make([]byte, 5)
+	header[0] = byte(frameInput)
+	binary.BigEndian.PutUint32(header[1:], maxFrameSize+1)
+	buf.Write(header)
+
+	if _, _, err := readFrame(&buf); err != ErrFrameTooLarge {
+		t.Fatalf("err = %v, want %v", err, ErrFrameTooLarge)
+	}
+}
+
+func TestResizePayloadRoundTrip(t *testing.T) {
+	payload := resizePayload(24, 80)
+	rows, cols := decodeResize(payload)
+	if rows != 24 || cols != 80 {
+		t.Fatalf("decodeResize = (%d, %d), want (24, 80)", rows, cols)
+	}
+}
+
+func TestModePayloadRoundTrip(t *testing.T) {
+	cases := []struct {
+		kind modeKind
+		on   bool
+	}{
+		{modeBracketedPaste, true},
+		{modeBracketedPaste, false},
+	}
+
+	for _, tc := range cases {
+		payload := modePayload(tc.kind, tc.on)
+		kind, on := decodeMode(payload)
+		if kind != tc.kind || on != tc.on {
+			t.Fatalf("decodeMode(modePayload(%v, %v)) = (%v, %v)", tc.kind, tc.on, kind, on)
+		}
+	}
+}
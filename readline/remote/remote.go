@@ -0,0 +1,230 @@
+// Package remote lets an ollama readline.Instance be driven over a
+// net.Conn instead of the local tty, so `ollama serve` can host
+// interactive chat sessions (`ollama attach <id>`) for thin clients that
+// have no local model access. Rendering - including the pager and
+// Ctrl+O tool-output view - stays on the server; the client only
+// forwards raw keystrokes and resize events and displays whatever bytes
+// the server sends back.
+package remote
+
+import (
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/ollama/ollama/readline"
+)
+
+// frameWriter implements io.Writer by wrapping every Write as a frame of
+// type typ on conn (frameOutput on the server, frameInput in Dial). mu is
+// held for the whole frame, so it is safe for concurrent use; other
+// goroutines that write frames on the same conn while it is in use
+// (Dial's resize forwarding) take mu as well, so frames never interleave.
+type frameWriter struct {
+	mu   sync.Mutex
+	conn net.Conn
+	typ  frameType
+}
+
+func (fw *frameWriter) Write(p []byte) (int, error) {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+
+	if err := writeFrame(fw.conn, fw.typ, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// clientSize tracks the most recently reported size of a remote client's
+// terminal, decoded from frameResize frames, so the server's pager and
+// completion columns (readline.Terminal.Size) can size themselves to the
+// client instead of the server's own tty.
+type clientSize struct {
+	mu         sync.Mutex
+	cols, rows int
+	known      bool
+}
+
+func (c *clientSize) set(cols, rows int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cols, c.rows = cols, rows
+	c.known = true
+}
+
+func (c *clientSize) get() (cols, rows int, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cols, c.rows, c.known
+}
+
+// Serve accepts connections on l and, for each one, builds a
+// readline.Instance whose Terminal reads frameInput frames and writes
+// frameOutput frames over that connection, then calls handler with it.
+// Serve blocks until l.Accept returns an error (e.g. the listener is
+// closed).
+func Serve(l net.Listener, handler func(*readline.Instance) error) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+
+		go serveConn(conn, handler)
+	}
+}
+
+func serveConn(conn net.Conn, handler func(*readline.Instance) error) {
+	defer conn.Close()
+
+	pr, pw := io.Pipe()
+	out := &frameWriter{conn: conn, typ: frameOutput}
+	term := readline.NewTerminalIO(pr, out)
+
+	size := &clientSize{}
+	term.SetSizeFn(size.get)
+
+	go func() {
+		for {
+			t, payload, err := readFrame(conn)
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+
+			switch t {
+			case frameInput:
+				if _, err := pw.Write(payload); err != nil {
+					return
+				}
+			case frameResize:
+				rows, cols := decodeResize(payload)
+				size.set(cols, rows)
+			}
+		}
+	}()
+
+	inst, err := readline.NewWithTerminal(readline.Prompt{}, term)
+	if err != nil {
+		return
+	}
+
+	// Tell the client to turn on bracketed paste so a pasted block
+	// arrives as a single CharBracketedPaste-wrapped frameInput instead
+	// of looking like fast typing; turn it back off once the session
+	// ends.
+	//nolint:errcheck
+	writeFrame(conn, frameMode, modePayload(modeBracketedPaste, true))
+	defer writeFrame(conn, frameMode, modePayload(modeBracketedPaste, false)) //nolint:errcheck
+
+	//nolint:errcheck
+	handler(inst)
+}
+
+// Dial connects to addr, puts the local tty into raw mode, and pipes
+// bytes bidirectionally: local keystrokes are sent as frameInput frames
+// and forwarded SIGWINCH events as frameResize frames, while frameOutput
+// frames received from the server are written straight to the local
+// terminal. It returns a readline.Instance wired to the connection so
+// callers that want the Instance surface (e.g. for cleanup) have one,
+// though the session's line editing happens server-side.
+func Dial(addr string) (*readline.Instance, error) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	fd := os.Stdin.Fd()
+	termios, err := readline.SetRawMode(fd)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	in := &frameWriter{conn: conn, typ: frameInput}
+	term := readline.NewTerminalIO(conn, os.Stdout)
+
+	// Report the local size up front so the server's pager and
+	// completion columns don't default to 80x24 until the next
+	// SIGWINCH.
+	if rows, cols, err := readline.TermSize(os.Stdout.Fd()); err == nil {
+		//nolint:errcheck
+		writeFrame(conn, frameResize, resizePayload(rows, cols))
+	}
+
+	go pipeStdin(conn, in, termios)
+	go pipeOutput(conn, termios)
+	go watchResize(in)
+
+	return readline.NewWithTerminal(readline.Prompt{}, term)
+}
+
+// pipeStdin forwards raw local keystrokes to the server as frameInput
+// frames until stdin or the connection errors, restoring the local tty
+// on exit.
+func pipeStdin(conn net.Conn, in *frameWriter, termios any) {
+	defer readline.UnsetRawMode(os.Stdin.Fd(), termios)
+
+	buf := make([]byte, 4096)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if n > 0 {
+			if _, werr := in.Write(buf[:n]); werr != nil {
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// pipeOutput reads frames from conn and writes frameOutput payloads
+// straight to the local terminal.
+func pipeOutput(conn net.Conn, termios any) {
+	defer readline.UnsetRawMode(os.Stdin.Fd(), termios)
+
+	for {
+		t, payload, err := readFrame(conn)
+		if err != nil {
+			return
+		}
+
+		switch t {
+		case frameOutput:
+			os.Stdout.Write(payload)
+		case frameMode:
+			if kind, on := decodeMode(payload); kind == modeBracketedPaste {
+				if on {
+					os.Stdout.WriteString("\x1b[?2004h")
+				} else {
+					os.Stdout.WriteString("\x1b[?2004l")
+				}
+			}
+		}
+	}
+}
+
+// watchResize forwards the local terminal's SIGWINCH signals to the
+// server as frameResize frames, holding in's lock so they cannot
+// interleave with the frameInput frames pipeStdin writes.
+func watchResize(in *frameWriter) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGWINCH)
+	defer signal.Stop(sig)
+
+	for range sig {
+		rows, cols, err := readline.TermSize(os.Stdout.Fd())
+		if err != nil {
+			continue
+		}
+		in.mu.Lock()
+		//nolint:errcheck
+		writeFrame(in.conn, frameResize, resizePayload(rows, cols))
+		in.mu.Unlock()
+	}
+}
@@ -0,0 +1,23 @@
+package remote
+
+import "testing"
+
+func TestClientSizeUnknownUntilSet(t *testing.T) {
+	var size clientSize
+
+	if _, _, ok := size.get(); ok {
+		t.Fatal("get() ok = true before any set, want false")
+	}
+
+	size.set(80, 24)
+	cols, rows, ok := size.get()
+	if !ok || cols != 80 || rows != 24 {
+		t.Fatalf("get() = (%d, %d, %v), want (80, 24, true)", cols, rows, ok)
+	}
+
+	size.set(120, 40)
+	cols, rows, ok = size.get()
+	if !ok || cols != 120 || rows != 40 {
+		t.Fatalf("get() after second set = (%d, %d, %v), want (120, 40, true)", cols, rows, ok)
+	}
+}
@@ -0,0 +1,128 @@
+package readline
+
+import (
+	"io"
+	"strings"
+)
+
+// searchState tracks an in-progress reverse-incremental history search
+// (Ctrl+R), local to a single Readline call.
+type searchState struct {
+	query    []rune
+	origPos  int    // i.History.Pos before the search started
+	origBuf  []rune // buf contents before the search started
+	matchIdx int    // index into i.History.Lines of the current match, -1 if none
+}
+
+// reverseSearch runs an interactive reverse-incremental search of
+// i.History, mirroring bash/liner's Ctrl+R UX. It returns the accepted
+// line and true if the user pressed Enter, or false if the search was
+// aborted (or exited via a cursor-movement key) and buf holds the final
+// state the caller should resume editing from.
+func (i *Instance) reverseSearch(buf *Buffer) (string, bool, error) {
+	s := &searchState{
+		origPos:  i.History.Pos,
+		origBuf:  []rune(buf.String()),
+		matchIdx: -1,
+	}
+
+	render := func() {
+		i.Terminal.write("\r" + ClearToEOL + s.render(i.History))
+	}
+
+	s.findMatch(i.History, true)
+	render()
+
+	for {
+		r, err := i.Terminal.Read()
+		if err != nil {
+			return "", false, io.EOF
+		}
+
+		switch r {
+		case CharCtrlR:
+			s.findMatch(i.History, true)
+			render()
+		case CharCtrlS:
+			s.findMatch(i.History, false)
+			render()
+		case CharBackspace, CharCtrlH:
+			if len(s.query) > 0 {
+				s.query = s.query[:len(s.query)-1]
+				// A shorter query can match entries newer than the
+				// current match that the longer query had already
+				// skipped past, so restart from the most recent entry
+				// rather than continuing backward from matchIdx.
+				s.matchIdx = -1
+				s.findMatch(i.History, true)
+				render()
+			}
+		case CharCtrlG, CharInterrupt:
+			i.History.Pos = s.origPos
+			buf.Replace(s.origBuf)
+			i.Terminal.write("\r" + ClearToEOL)
+			return "", false, nil
+		case CharEnter, CharCtrlJ:
+			if s.matchIdx >= 0 {
+				buf.Replace([]rune(i.History.Lines[s.matchIdx]))
+			}
+			i.Terminal.write("\r" + ClearToEOL)
+			return buf.String(), true, nil
+		case CharEsc, CharLineStart, CharLineEnd, CharBackward, CharForward:
+			// Any cursor-movement key accepts the current match and exits
+			// search mode without submitting the line.
+			if s.matchIdx >= 0 {
+				buf.Replace([]rune(i.History.Lines[s.matchIdx]))
+			}
+			i.Terminal.write("\r" + ClearToEOL)
+			return "", false, nil
+		default:
+			if r >= CharSpace {
+				s.query = append(s.query, r)
+				s.findMatch(i.History, true)
+				render()
+			}
+		}
+	}
+}
+
+// findMatch delegates to History.Search for the nearest entry containing
+// the query, moving backward (older) or forward (newer) from the
+// current matchIdx.
+func (s *searchState) findMatch(h *History, backward bool) {
+	if len(s.query) == 0 {
+		s.matchIdx = -1
+		return
+	}
+
+	from := s.matchIdx
+	if from < 0 {
+		from = len(h.Lines)
+	}
+
+	if idx, _, ok := h.Search(string(s.query), backward, from); ok {
+		s.matchIdx = idx
+	}
+}
+
+// render draws the "(reverse-i-search)" status line with the matched
+// region of the candidate entry highlighted.
+func (s *searchState) render(h *History) string {
+	query := string(s.query)
+	prefix := "(reverse-i-search)'" + query + "': "
+
+	if s.matchIdx < 0 || len(query) == 0 {
+		return prefix
+	}
+
+	line := h.Lines[s.matchIdx]
+	at := strings.Index(line, query)
+	if at < 0 {
+		return prefix + line
+	}
+
+	return prefix +
+		ColorGrey + line[:at] + ColorDefault +
+		line[at:at+len(query)] +
+		ColorGrey + line[at+len(query):] + ColorDefault
+}
@@ -0,0 +1,50 @@
+package readline
+
+import "testing"
+
+func TestSearchStateFindMatchBackward(t *testing.T) {
+	h := &History{Lines: []string{"ollama run llama3", "ollama pull mistral", "ls -la"}}
+
+	s := &searchState{matchIdx: -1}
+	s.query = []rune("ollama")
+	s.findMatch(h, true)
+
+	if s.matchIdx != 1 {
+		t.Fatalf("matchIdx = %d, want 1 (ollama pull mistral)", s.matchIdx)
+	}
+}
+
+func TestSearchStateBackspaceRestartsFromNewestEntry(t *testing.T) {
+	h := &History{Lines: []string{"ollama pull mistral", "ollama run llama3", "ls -la"}}
+
+	s := &searchState{matchIdx: -1}
+
+	// Narrow the query down to something that only matches the oldest
+	// entry, landing matchIdx on it.
+	s.query = []rune("pull")
+	s.findMatch(h, true)
+	if s.matchIdx != 0 {
+		t.Fatalf("matchIdx = %d, want 0 (ollama pull mistral)", s.matchIdx)
+	}
+
+	// Backspacing to a shorter query that also matches the newer entry
+	// must find it, not stay stuck on the stale match.
+	s.query = []rune("ll")
+	s.matchIdx = -1
+	s.findMatch(h, true)
+
+	if s.matchIdx != 1 {
+		t.Fatalf("matchIdx = %d, want 1 (ollama run llama3) after backspace re-seed", s.matchIdx)
+	}
+}
+
+func TestSearchStateRenderHighlightsMatch(t *testing.T) {
+	h := &History{Lines: []string{"ollama run llama3"}}
+	s := &searchState{query: []rune("run"), matchIdx: 0}
+
+	got := s.render(h)
+	want := "(reverse-i-search)'run': " + ColorGrey + "ollama " + ColorDefault + "run" + ColorGrey + " llama3" + ColorDefault
+	if got != want {
+		t.Fatalf("render() = %q, want %q", got, want)
+	}
+}
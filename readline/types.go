@@ -0,0 +1,116 @@
+package readline
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// ErrInterrupt is returned by Instance.Readline when the user presses Ctrl+C.
+var ErrInterrupt = errors.New("interrupt")
+
+// Control characters and escape sequence markers recognized by Readline.
+const (
+	CharNull      rune = 0
+	CharLineStart rune = 1  // Ctrl+A
+	CharBackward  rune = 2  // Ctrl+B
+	CharInterrupt rune = 3  // Ctrl+C
+	CharDelete    rune = 4  // Ctrl+D
+	CharLineEnd   rune = 5  // Ctrl+E
+	CharForward   rune = 6  // Ctrl+F
+	CharCtrlG     rune = 7  // Ctrl+G
+	CharCtrlH     rune = 8  // Ctrl+H
+	CharTab       rune = 9  // Ctrl+I
+	CharCtrlJ     rune = 10 // Ctrl+J (line feed)
+	CharKill      rune = 11 // Ctrl+K
+	CharCtrlL     rune = 12 // Ctrl+L
+	CharEnter     rune = 13 // Ctrl+M
+	CharNext      rune = 14 // Ctrl+N
+	CharCtrlO     rune = 15 // Ctrl+O
+	CharPrev      rune = 16 // Ctrl+P
+	CharCtrlR     rune = 18 // Ctrl+R
+	CharCtrlS     rune = 19 // Ctrl+S
+	CharCtrlU     rune = 21 // Ctrl+U
+	CharCtrlW     rune = 23 // Ctrl+W
+	CharCtrlZ     rune = 26 // Ctrl+Z
+	CharEsc       rune = 27
+	CharSpace     rune = 32
+	CharBackspace rune = 127
+
+	// CharEscapeEx marks the second byte of a two-byte escape sequence ('[').
+	CharEscapeEx rune = '['
+
+	// CharBracketedPaste marks the start of a bracketed-paste escape
+	// sequence (ESC [ 2 ...); the remaining bytes are read and compared
+	// against CharBracketedPasteStart/CharBracketedPasteEnd.
+	CharBracketedPaste      rune   = '2'
+	CharBracketedPasteStart string = "00~"
+	CharBracketedPasteEnd   string = "01~"
+
+	// Arrow and navigation keys, the third byte of an ESC [ sequence.
+	KeyUp    rune = 'A'
+	KeyDown  rune = 'B'
+	KeyRight rune = 'C'
+	KeyLeft  rune = 'D'
+	KeyDel   rune = '3'
+
+	// MetaStart/MetaEnd are Home/End (ESC [ H / ESC [ F).
+	MetaStart rune = 'H'
+	MetaEnd   rune = 'F'
+)
+
+// ANSI SGR color sequences used to render placeholders and pager chrome.
+const (
+	ColorGrey    = "\x1b[90m"
+	ColorDefault = "\x1b[0m"
+)
+
+// Cursor and screen control sequences.
+const (
+	ClearToEOL     = "\x1b[0K"
+	ClearScreen    = "\x1b[2J"
+	CursorReset    = "\x1b[H"
+	EnterAltScreen = "\x1b[?1049h"
+	ExitAltScreen  = "\x1b[?1049l"
+)
+
+// CursorLeftN returns the escape sequence that moves the cursor left n columns.
+func CursorLeftN(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("\x1b[%dD", n)
+}
+
+// visibleWidth returns the number of printable runes in b, skipping ANSI
+// SGR escape sequences ("\x1b[...m") so callers can size cursor movement
+// against colorized output (e.g. a Highlighter or Hinter result) the same
+// way they would against plain text.
+func visibleWidth(b []byte) int {
+	width := 0
+	for i := 0; i < len(b); {
+		if b[i] == 0x1b && i+1 < len(b) && b[i+1] == '[' {
+			i += 2
+			for i < len(b) && b[i] != 'm' {
+				i++
+			}
+			if i < len(b) {
+				i++ // skip the trailing 'm'
+			}
+			continue
+		}
+
+		_, size := utf8.DecodeRune(b[i:])
+		width++
+		i += size
+	}
+	return width
+}
+
+// TermSize returns the row and column count of the tty identified by fd.
+func TermSize(fd uintptr) (rows, cols int, err error) {
+	cols, rows, err = term.GetSize(int(fd))
+	return rows, cols, err
+}
@@ -0,0 +1,433 @@
+package readline
+
+// EditMode selects the key dispatch style used by Instance.Readline.
+type EditMode int
+
+const (
+	// ModeEmacs is the default: every key is a direct single-key binding
+	// (either the built-in switch or an entry in Instance.Keymap).
+	ModeEmacs EditMode = iota
+	// ModeVi adds a vi-style modal keymap: Readline starts in insert mode
+	// (identical to ModeEmacs) until Esc switches to normal mode, where
+	// motions, operators and text objects are composed like real vi.
+	ModeVi
+)
+
+// viState holds the per-Readline-call state for vi normal mode: whether
+// we're currently in insert mode, a pending operator/count, the last
+// yank/delete register, the last change (for '.' repeat), and the
+// single-level undo snapshot.
+type viState struct {
+	insert bool
+
+	count   int
+	pending rune // pending operator ('d', 'c', or 'y'), 0 if none
+	opCount int  // count collected before pending was set, e.g. the "2" in 2dw
+
+	register string // last yanked/deleted text, for p/P
+
+	lastCmd func(i *Instance, buf *Buffer) // repeated by '.'
+
+	// insertStart and insertSetup track an in-progress insert entered via
+	// a vi command (i/a/I/A or a 'c' operator): insertStart is the buffer
+	// position insert began at, and insertSetup - run again at the
+	// repeated position and followed by re-inserting the same text, as
+	// lastCmd - is the motion/delete that got us there. Both are cleared
+	// once Esc turns them into a finished lastCmd.
+	insertStart int
+	insertSetup func(i *Instance, buf *Buffer)
+
+	undo    []rune
+	undoPos int
+	hasUndo bool
+}
+
+// newViState returns a viState starting in insert mode, matching vi's
+// behavior when entering a fresh command line.
+func newViState() *viState {
+	return &viState{insert: true}
+}
+
+// viSaveUndo snapshots buf so a subsequent viUndo can restore it. vi only
+// supports a single level of undo for the current line, so this overwrites
+// any previous snapshot.
+func (i *Instance) viSaveUndo(buf *Buffer) {
+	i.vi.undo = append([]rune{}, buf.Buf...)
+	i.vi.undoPos = buf.Pos
+	i.vi.hasUndo = true
+}
+
+// viUndo restores the last snapshot taken by viSaveUndo, if any.
+func (i *Instance) viUndo(buf *Buffer) {
+	if !i.vi.hasUndo {
+		return
+	}
+	saved := i.vi.undo
+	savedPos := i.vi.undoPos
+	i.viSaveUndo(buf)
+	buf.Replace(saved)
+	buf.MoveTo(savedPos)
+}
+
+// viEndInsert runs when Esc exits vi insert mode. If insert was entered
+// via i/a/I/A or a 'c' operator, insertSetup is set; this captures the
+// text typed since insertStart and turns insertSetup into a lastCmd that
+// '.' can replay: redo the same motion/delete (recomputed against
+// whatever the buffer looks like at replay time), then re-insert the
+// same text.
+func (i *Instance) viEndInsert(buf *Buffer) {
+	v := i.vi
+	if v.insertSetup == nil {
+		return
+	}
+
+	start, end := v.insertStart, buf.Pos
+	if end < start {
+		start, end = end, start
+	}
+	text := string(buf.Buf[start:end])
+
+	setup := v.insertSetup
+	v.insertSetup = nil
+	v.lastCmd = func(i *Instance, buf *Buffer) {
+		setup(i, buf)
+		buf.InsertString(text)
+	}
+}
+
+// viNormal handles a single key read while in vi normal mode. It returns
+// (output, true, nil) when the line should be submitted, mirroring the
+// CharEnter case in the main Readline loop.
+func (i *Instance) viNormal(buf *Buffer, r rune) (string, bool, error) {
+	v := i.vi
+
+	// A digit 1-9 (or a leading run of digits) accumulates a count prefix;
+	// '0' is only a count digit if a count is already being built, since a
+	// bare '0' is the "start of line" motion.
+	if r >= '1' && r <= '9' || (r == '0' && v.count > 0) {
+		v.count = v.count*10 + int(r-'0')
+		return "", false, nil
+	}
+	count := v.count
+	if count == 0 {
+		count = 1
+	}
+	v.count = 0
+
+	if v.pending != 0 {
+		op := v.pending
+		opCount := v.opCount
+		v.pending = 0
+		v.opCount = 0
+		total := opCount * count
+
+		// Text objects (iw, a", ...) name the object with one more key,
+		// which viDoOperator needs again on every '.' replay, so resolve
+		// it once here rather than re-reading the terminal on replay.
+		var obj rune
+		if r == 'i' || r == 'a' {
+			o, err := i.Terminal.Read()
+			if err != nil {
+				return "", false, nil
+			}
+			obj = o
+		}
+
+		i.viDoOperator(buf, op, r, obj, total, true)
+
+		switch op {
+		case 'c':
+			v.insertStart = buf.Pos
+			v.insertSetup = func(i *Instance, buf *Buffer) {
+				i.viDoOperator(buf, op, r, obj, total, false)
+			}
+		case 'd':
+			v.lastCmd = func(i *Instance, buf *Buffer) {
+				i.viDoOperator(buf, op, r, obj, total, false)
+			}
+		}
+		return "", false, nil
+	}
+
+	switch r {
+	case 'i':
+		v.insert = true
+		v.insertStart = buf.Pos
+		v.insertSetup = func(i *Instance, buf *Buffer) {}
+	case 'a':
+		buf.MoveRight()
+		v.insert = true
+		v.insertStart = buf.Pos
+		v.insertSetup = func(i *Instance, buf *Buffer) { buf.MoveRight() }
+	case 'I':
+		buf.MoveTo(firstNonBlank(buf))
+		v.insert = true
+		v.insertStart = buf.Pos
+		v.insertSetup = func(i *Instance, buf *Buffer) { buf.MoveTo(firstNonBlank(buf)) }
+	case 'A':
+		buf.MoveToEnd()
+		v.insert = true
+		v.insertStart = buf.Pos
+		v.insertSetup = func(i *Instance, buf *Buffer) { buf.MoveToEnd() }
+	case 'h':
+		for range count {
+			buf.MoveLeft()
+		}
+	case 'l':
+		for range count {
+			buf.MoveRight()
+		}
+	case 'j', CharNext:
+		var discard []rune
+		i.historyNext(buf, &discard)
+	case 'k', CharPrev:
+		var discard []rune
+		i.historyPrev(buf, &discard)
+	case '0':
+		buf.MoveTo(0)
+	case '^':
+		buf.MoveTo(firstNonBlank(buf))
+	case '$':
+		buf.MoveToEnd()
+	case 'w':
+		for range count {
+			buf.MoveRightWord()
+		}
+	case 'b':
+		for range count {
+			buf.MoveLeftWord()
+		}
+	case 'e':
+		for range count {
+			buf.MoveTo(viWordEnd(buf, buf.Pos))
+		}
+	case 'x':
+		i.viSaveUndo(buf)
+		end := buf.Pos + count
+		if end > len(buf.Buf) {
+			end = len(buf.Buf)
+		}
+		v.register = buf.DeleteRange(buf.Pos, end)
+		v.lastCmd = func(i *Instance, buf *Buffer) {
+			i.viSaveUndo(buf)
+			end := buf.Pos + count
+			if end > len(buf.Buf) {
+				end = len(buf.Buf)
+			}
+			i.vi.register = buf.DeleteRange(buf.Pos, end)
+		}
+	case 'd', 'c', 'y':
+		v.pending = r
+		v.opCount = count
+	case 'D':
+		i.viSaveUndo(buf)
+		v.register = buf.DeleteRange(buf.Pos, len(buf.Buf))
+		v.lastCmd = func(i *Instance, buf *Buffer) {
+			i.viSaveUndo(buf)
+			i.vi.register = buf.DeleteRange(buf.Pos, len(buf.Buf))
+		}
+	case 'C':
+		i.viSaveUndo(buf)
+		v.register = buf.DeleteRange(buf.Pos, len(buf.Buf))
+		v.insert = true
+		v.insertStart = buf.Pos
+		v.insertSetup = func(i *Instance, buf *Buffer) {
+			i.viSaveUndo(buf)
+			buf.DeleteRange(buf.Pos, len(buf.Buf))
+		}
+	case 'p':
+		i.viSaveUndo(buf)
+		buf.MoveRight()
+		buf.InsertString(v.register)
+		v.lastCmd = func(i *Instance, buf *Buffer) {
+			i.viSaveUndo(buf)
+			buf.MoveRight()
+			buf.InsertString(i.vi.register)
+		}
+	case 'P':
+		i.viSaveUndo(buf)
+		buf.InsertString(v.register)
+		v.lastCmd = func(i *Instance, buf *Buffer) {
+			i.viSaveUndo(buf)
+			buf.InsertString(i.vi.register)
+		}
+	case 'u':
+		i.viUndo(buf)
+	case '.':
+		if v.lastCmd != nil {
+			v.lastCmd(i, buf)
+		}
+	case CharInterrupt:
+		return "", true, ErrInterrupt
+	case CharEnter, CharCtrlJ:
+		output := buf.String()
+		if output != "" {
+			i.History.Add(output)
+		}
+		buf.MoveToEnd()
+		i.Terminal.write("\n")
+		return output, true, nil
+	}
+
+	return "", false, nil
+}
+
+// viOperatorRange resolves the [start, end) buffer range that operator op
+// acting on motion/text-object r (with the object char obj, meaningful
+// only when r is 'i' or 'a') and count affects. It has no side effects on
+// buf beyond what the motions themselves read, so viDoOperator can call
+// it again at replay time to recompute the range against whatever the
+// buffer looks like then, rather than replaying stale indices.
+func viOperatorRange(buf *Buffer, op, r, obj rune, count int) (start, end int) {
+	switch {
+	case r == op: // dd, cc, yy
+		return 0, len(buf.Buf)
+	case r == 'i' || r == 'a':
+		inner := r == 'i'
+		switch obj {
+		case 'w':
+			return wordObjectRange(buf, inner)
+		case '"', '\'':
+			return quoteObjectRange(buf, inner, byte(obj))
+		default:
+			return buf.Pos, buf.Pos
+		}
+	case r == 'w':
+		start = buf.Pos
+		end = buf.Pos
+		for range count {
+			end = buf.wordRight(end)
+		}
+		return start, end
+	case r == 'b':
+		end = buf.Pos
+		start = end
+		for range count {
+			start = buf.wordLeft(start)
+		}
+		return start, end
+	case r == 'e':
+		start = buf.Pos
+		end = start
+		for range count {
+			end = viWordEnd(buf, end) + 1
+		}
+		return start, end
+	case r == '0':
+		return 0, buf.Pos
+	case r == '^':
+		return firstNonBlank(buf), buf.Pos
+	case r == '$':
+		return buf.Pos, len(buf.Buf)
+	default:
+		return buf.Pos, buf.Pos
+	}
+}
+
+// viDoOperator resolves a pending operator (d/c/y) against the motion or
+// text object named by r (and obj, for text objects), applying it to buf
+// and stashing the affected text in v.register. A doubled operator (dd,
+// cc, yy) acts on the whole line. enterInsert controls whether a 'c'
+// leaves the Instance in insert mode: true for the live keypress, false
+// when '.' replays the change, since replay shouldn't flip editing modes
+// out from under the caller.
+func (i *Instance) viDoOperator(buf *Buffer, op, r, obj rune, count int, enterInsert bool) {
+	v := i.vi
+
+	start, end := viOperatorRange(buf, op, r, obj, count)
+	if start > end {
+		start, end = end, start
+	}
+
+	switch op {
+	case 'y':
+		if start < end {
+			v.register = string(buf.Buf[start:end])
+		}
+		buf.MoveTo(start)
+	case 'd':
+		i.viSaveUndo(buf)
+		v.register = buf.DeleteRange(start, end)
+	case 'c':
+		i.viSaveUndo(buf)
+		v.register = buf.DeleteRange(start, end)
+		if enterInsert {
+			v.insert = true
+		}
+	}
+}
+
+// firstNonBlank returns the index of the first non-space rune in buf, or
+// the end of the buffer if it is all spaces.
+func firstNonBlank(buf *Buffer) int {
+	i := 0
+	for i < len(buf.Buf) && buf.Buf[i] == ' ' {
+		i++
+	}
+	return i
+}
+
+// viWordEnd returns the index of the last rune of the word at or after
+// pos, vi's "e" motion target.
+func viWordEnd(buf *Buffer, pos int) int {
+	i := pos
+	if i < len(buf.Buf)-1 {
+		i++
+	}
+	for i < len(buf.Buf) && buf.Buf[i] == ' ' {
+		i++
+	}
+	for i < len(buf.Buf)-1 && buf.Buf[i+1] != ' ' {
+		i++
+	}
+	return i
+}
+
+// wordObjectRange returns the [start, end) range of the word text object
+// under the cursor: "aw" includes trailing whitespace, "iw" does not.
+func wordObjectRange(buf *Buffer, inner bool) (int, int) {
+	pos := buf.Pos
+	if pos >= len(buf.Buf) {
+		return pos, pos
+	}
+
+	start := pos
+	for start > 0 && buf.Buf[start-1] != ' ' {
+		start--
+	}
+	end := pos
+	for end < len(buf.Buf) && buf.Buf[end] != ' ' {
+		end++
+	}
+	if !inner {
+		for end < len(buf.Buf) && buf.Buf[end] == ' ' {
+			end++
+		}
+	}
+	return start, end
+}
+
+// quoteObjectRange returns the [start, end) range of the quoted text
+// object delimited by quote: "a\"" includes the quotes, "i\"" does not.
+func quoteObjectRange(buf *Buffer, inner bool, quote byte) (int, int) {
+	open := -1
+	for i := 0; i < len(buf.Buf); i++ {
+		if byte(buf.Buf[i]) != quote {
+			continue
+		}
+		if open < 0 {
+			open = i
+			continue
+		}
+		closeIdx := i
+		if buf.Pos < open || buf.Pos > closeIdx {
+			open = -1
+			continue
+		}
+		if inner {
+			return open + 1, closeIdx
+		}
+		return open, closeIdx + 1
+	}
+	return buf.Pos, buf.Pos
+}
@@ -0,0 +1,110 @@
+package readline
+
+import (
+	"io"
+	"testing"
+)
+
+func newTestViBuffer(t *testing.T, line string, pos int) (*Instance, *Buffer) {
+	t.Helper()
+
+	i := &Instance{Terminal: &Terminal{out: io.Discard}, vi: newViState()}
+	buf, err := NewBuffer(&Prompt{}, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Buf = []rune(line)
+	buf.Pos = pos
+	return i, buf
+}
+
+func pressKeys(t *testing.T, i *Instance, buf *Buffer, keys string) {
+	t.Helper()
+	for _, r := range keys {
+		if _, _, err := i.viNormal(buf, r); err != nil {
+			t.Fatalf("viNormal(%q) error: %v", r, err)
+		}
+	}
+}
+
+func TestViOperatorsAndMotions(t *testing.T) {
+	cases := []struct {
+		name string
+		line string
+		pos  int
+		keys string
+		want string
+	}{
+		{name: "dw deletes to next word", line: "foo bar baz", pos: 0, keys: "dw", want: "bar baz"},
+		{name: "2dw deletes two words", line: "one two three four", pos: 0, keys: "2dw", want: "three four"},
+		{name: "dd deletes the whole line", line: "foo bar", pos: 3, keys: "dd", want: ""},
+		{name: "yy then p pastes the line after the cursor", line: "foo", pos: 0, keys: "yyp", want: "ffoooo"},
+		{name: "yy then P duplicates the line before cursor", line: "foo", pos: 0, keys: "yyP", want: "foofoo"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			i, buf := newTestViBuffer(t, tc.line, tc.pos)
+			pressKeys(t, i, buf, tc.keys)
+			if got := buf.String(); got != tc.want {
+				t.Fatalf("after %q: buf = %q, want %q", tc.keys, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestViDotRepeatsLastDelete(t *testing.T) {
+	i, buf := newTestViBuffer(t, "foo bar baz", 0)
+
+	pressKeys(t, i, buf, "dw")
+	if got := buf.String(); got != "bar baz" {
+		t.Fatalf("after dw: buf = %q, want %q", got, "bar baz")
+	}
+
+	pressKeys(t, i, buf, ".")
+	if got := buf.String(); got != "baz" {
+		t.Fatalf("after dw.: buf = %q, want %q", got, "baz")
+	}
+}
+
+func TestViDotRepeatsChangeWithSameInsertedText(t *testing.T) {
+	i, buf := newTestViBuffer(t, "foo bar baz", 0)
+
+	pressKeys(t, i, buf, "cw")
+	if !i.vi.insert {
+		t.Fatal("cw should enter insert mode")
+	}
+	if got := buf.String(); got != "bar baz" {
+		t.Fatalf("after cw delete: buf = %q, want %q", got, "bar baz")
+	}
+
+	// Simulate typing "NEW" during insert mode, then Esc - the part the
+	// main Readline loop normally drives.
+	buf.InsertString("NEW")
+	i.vi.insert = false
+	i.viEndInsert(buf)
+
+	if got := buf.String(); got != "NEWbar baz" {
+		t.Fatalf("after cwNEW<Esc>: buf = %q, want %q", got, "NEWbar baz")
+	}
+
+	pressKeys(t, i, buf, ".")
+	if got := buf.String(); got != "NEWNEWbaz" {
+		t.Fatalf("after cwNEW<Esc>.: buf = %q, want %q", got, "NEWNEWbaz")
+	}
+}
+
+func TestViCountBeforeAndAfterOperatorAgree(t *testing.T) {
+	before, beforeBuf := newTestViBuffer(t, "one two three four", 0)
+	pressKeys(t, before, beforeBuf, "2dw")
+
+	after, afterBuf := newTestViBuffer(t, "one two three four", 0)
+	pressKeys(t, after, afterBuf, "d2w")
+
+	if beforeBuf.String() != afterBuf.String() {
+		t.Fatalf("2dw = %q, d2w = %q, want equal", beforeBuf.String(), afterBuf.String())
+	}
+	if want := "three four"; beforeBuf.String() != want {
+		t.Fatalf("2dw = %q, want %q", beforeBuf.String(), want)
+	}
+}